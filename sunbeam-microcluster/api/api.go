@@ -0,0 +1,12 @@
+package api
+
+import "github.com/canonical/microcluster/v2/rest"
+
+// Endpoints lists every /1.0/* REST endpoint sunbeam-microcluster serves,
+// for attaching to the microcluster daemon's API resources at startup.
+var Endpoints = []rest.Endpoint{
+	storageBackendsCmd,
+	storageBackendCmd,
+	jujuusersCmd,
+	jujuuserCmd,
+}
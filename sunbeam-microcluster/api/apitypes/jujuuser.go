@@ -0,0 +1,22 @@
+package apitypes
+
+// JujuUsers holds a list of JujuUser type
+type JujuUsers []JujuUser
+
+// JujuUser structure to hold juju user details like username and token
+type JujuUser struct {
+	// Username is the name of the juju user
+	Username string `json:"username" yaml:"username"`
+	// Token is the juju user's authentication token
+	Token string `json:"token" yaml:"token"`
+	// Revision is incremented on every update. Clients performing a PUT or
+	// DELETE must supply the revision they last observed via the If-Match
+	// header, echoed back by GET as the ETag header.
+	Revision int64 `json:"revision" yaml:"revision"`
+}
+
+// JujuUserFilter is used to filter juju users returned by GET /1.0/jujuusers.
+type JujuUserFilter struct {
+	// Username filters to the juju user with this username
+	Username *string
+}
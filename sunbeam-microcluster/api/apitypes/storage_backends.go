@@ -10,10 +10,29 @@ type StorageBackend struct {
 	Name string `json:"name" yaml:"name"`
 	// Type of the storage backend
 	Type string `json:"type" yaml:"type"`
-	// Config holds backend specific configuration as a json blob
+	// Config holds backend specific configuration as a json blob, validated
+	// server-side against the schema registered for Type in the
+	// configschema package
 	Config string `json:"config" yaml:"config"`
 	// Name of the principal application this storage backend is associated with
 	Principal string `json:"principal" yaml:"principal"`
 	// ModelUUID is the juju model UUID where this storage backend is deployed
 	ModelUUID string `json:"model-uuid" yaml:"model-uuid"`
+	// Revision is incremented on every update. Clients performing a PUT or
+	// DELETE must supply the revision they last observed via the If-Match
+	// header, echoed back by GET as the ETag header.
+	Revision int64 `json:"revision" yaml:"revision"`
+}
+
+// StorageBackendFilter is used to filter storage backends returned by
+// GET /1.0/storage-backend.
+type StorageBackendFilter struct {
+	// Name filters to the storage backend with this name
+	Name *string
+	// Type filters to storage backends of this type
+	Type *string
+	// Principal filters to storage backends associated with this principal application
+	Principal *string
+	// ModelUUID filters to storage backends deployed in this juju model
+	ModelUUID *string
 }
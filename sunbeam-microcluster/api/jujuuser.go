@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/shared/api"
@@ -35,7 +36,13 @@ var jujuuserCmd = rest.Endpoint{
 }
 
 func cmdJujuUsersGetAll(s state.State, r *http.Request) response.Response {
-	users, err := sunbeam.ListJujuUsers(r.Context(), s)
+	filter := apitypes.JujuUserFilter{}
+
+	if username := r.URL.Query().Get("username"); username != "" {
+		filter.Username = &username
+	}
+
+	users, err := sunbeam.ListJujuUsers(r.Context(), s, filter)
 	if err != nil {
 		return response.InternalError(err)
 	}
@@ -61,7 +68,7 @@ func cmdJujuUsersGet(s state.State, r *http.Request) response.Response {
 		return response.InternalError(err)
 	}
 
-	return response.SyncResponse(true, jujuUser)
+	return response.SyncResponseHeaders(true, jujuUser, map[string]string{"ETag": strconv.Quote(strconv.FormatInt(jujuUser.Revision, 10))})
 }
 
 func cmdJujuUsersPost(s state.State, r *http.Request) response.Response {
@@ -88,19 +95,27 @@ func cmdJujuUsersPut(s state.State, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	expectedRevision, err := requireIfMatch(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		return response.InternalError(err)
 	}
 
-	err = sunbeam.UpdateJujuUser(r.Context(), s, name, req.Token)
+	err = sunbeam.UpdateJujuUser(r.Context(), s, name, req.Token, expectedRevision)
 	if err != nil {
-		// Return the appropriate error if juju user is not found
+		// Return the appropriate error if juju user is not found or was concurrently modified
 		var statusErr api.StatusError
 		if errors.As(err, &statusErr) {
 			if statusErr.Status() == http.StatusNotFound {
 				return response.NotFound(err)
 			}
+			if statusErr.Status() == http.StatusPreconditionFailed {
+				return response.PreconditionFailed(err)
+			}
 		}
 		return response.InternalError(err)
 	}
@@ -113,8 +128,23 @@ func cmdJujuUsersDelete(s state.State, r *http.Request) response.Response {
 	if err != nil {
 		return response.SmartError(err)
 	}
-	err = sunbeam.DeleteJujuUser(r.Context(), s, name)
+
+	expectedRevision, err := requireIfMatch(r)
 	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = sunbeam.DeleteJujuUser(r.Context(), s, name, expectedRevision)
+	if err != nil {
+		var statusErr api.StatusError
+		if errors.As(err, &statusErr) {
+			if statusErr.Status() == http.StatusNotFound {
+				return response.NotFound(err)
+			}
+			if statusErr.Status() == http.StatusPreconditionFailed {
+				return response.PreconditionFailed(err)
+			}
+		}
 		return response.InternalError(err)
 	}
 
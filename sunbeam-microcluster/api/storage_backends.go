@@ -2,8 +2,12 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/shared/api"
@@ -13,6 +17,7 @@ import (
 
 	"github.com/canonical/snap-openstack/sunbeam-microcluster/access"
 	"github.com/canonical/snap-openstack/sunbeam-microcluster/api/apitypes"
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/configschema"
 	"github.com/canonical/snap-openstack/sunbeam-microcluster/sunbeam"
 )
 
@@ -34,8 +39,20 @@ var storageBackendCmd = rest.Endpoint{
 }
 
 func cmdStorageBackendsGetAll(s state.State, r *http.Request) response.Response {
+	filter := apitypes.StorageBackendFilter{}
 
-	storageBackends, err := sunbeam.ListStorageBackends(r.Context(), s)
+	query := r.URL.Query()
+	if backendType := query.Get("type"); backendType != "" {
+		filter.Type = &backendType
+	}
+	if principal := query.Get("principal"); principal != "" {
+		filter.Principal = &principal
+	}
+	if modelUUID := query.Get("model-uuid"); modelUUID != "" {
+		filter.ModelUUID = &modelUUID
+	}
+
+	storageBackends, err := sunbeam.ListStorageBackends(r.Context(), s, filter)
 	if err != nil {
 		return response.InternalError(err)
 	}
@@ -51,7 +68,12 @@ func cmdStorageBackendsPost(s state.State, r *http.Request) response.Response {
 		return response.InternalError(err)
 	}
 
-	err = sunbeam.AddStorageBackend(r.Context(), s, req.Name, req.Type, req.Principal, req.ModelUUID, req.Config)
+	normalizedConfig, err := normalizeStorageBackendConfig(req.Type, req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = sunbeam.AddStorageBackend(r.Context(), s, req.Name, req.Type, req.Principal, req.ModelUUID, normalizedConfig)
 	if err != nil {
 		return response.InternalError(err)
 	}
@@ -59,6 +81,18 @@ func cmdStorageBackendsPost(s state.State, r *http.Request) response.Response {
 	return response.EmptySyncResponse
 }
 
+// normalizeStorageBackendConfig validates a client-supplied config blob
+// against the schema registered for backendType and returns its normalized
+// JSON representation, ready for persistence.
+func normalizeStorageBackendConfig(backendType string, rawConfig string) (string, error) {
+	cfg, err := configschema.Unmarshal(backendType, []byte(rawConfig))
+	if err != nil {
+		return "", err
+	}
+
+	return configschema.Marshal(cfg)
+}
+
 func cmdStorageBackendGet(s state.State, r *http.Request) response.Response {
 	var backendName string
 	backendName, err := url.PathUnescape(mux.Vars(r)["backendname"])
@@ -67,15 +101,16 @@ func cmdStorageBackendGet(s state.State, r *http.Request) response.Response {
 	}
 	backend, err := sunbeam.GetStorageBackend(r.Context(), s, backendName)
 	if err != nil {
-		if err, ok := err.(api.StatusError); ok {
-			if err.Status() == http.StatusNotFound {
+		var statusErr api.StatusError
+		if errors.As(err, &statusErr) {
+			if statusErr.Status() == http.StatusNotFound {
 				return response.NotFound(err)
 			}
 		}
 		return response.InternalError(err)
 	}
 
-	return response.SyncResponse(true, backend)
+	return response.SyncResponseHeaders(true, backend, map[string]string{"ETag": strconv.Quote(strconv.FormatInt(backend.Revision, 10))})
 }
 
 func cmdStorageBackendDelete(s state.State, r *http.Request) response.Response {
@@ -83,12 +118,22 @@ func cmdStorageBackendDelete(s state.State, r *http.Request) response.Response {
 	if err != nil {
 		return response.SmartError(err)
 	}
-	err = sunbeam.DeleteStorageBackend(r.Context(), s, backendName)
+
+	expectedRevision, err := requireIfMatch(r)
 	if err != nil {
-		if err, ok := err.(api.StatusError); ok {
-			if err.Status() == http.StatusNotFound {
+		return response.BadRequest(err)
+	}
+
+	err = sunbeam.DeleteStorageBackend(r.Context(), s, backendName, expectedRevision)
+	if err != nil {
+		var statusErr api.StatusError
+		if errors.As(err, &statusErr) {
+			if statusErr.Status() == http.StatusNotFound {
 				return response.NotFound(err)
 			}
+			if statusErr.Status() == http.StatusPreconditionFailed {
+				return response.PreconditionFailed(err)
+			}
 		}
 		return response.InternalError(err)
 	}
@@ -102,16 +147,70 @@ func cmdStorageBackendPut(s state.State, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	expectedRevision, err := requireIfMatch(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	var req apitypes.StorageBackend
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		return response.InternalError(err)
 	}
 
-	err = sunbeam.UpdateStorageBackend(r.Context(), s, backendName, req.Type, req.Config, req.Principal, req.ModelUUID)
+	if req.Config != "" {
+		backendType := req.Type
+		if backendType == "" {
+			// Partial update: the client didn't resend Type, so validate
+			// the new config against the backend's existing type.
+			existing, err := sunbeam.GetStorageBackend(r.Context(), s, backendName)
+			if err != nil {
+				var statusErr api.StatusError
+				if errors.As(err, &statusErr) {
+					if statusErr.Status() == http.StatusNotFound {
+						return response.NotFound(err)
+					}
+				}
+				return response.InternalError(err)
+			}
+			backendType = existing.Type
+		}
+
+		req.Config, err = normalizeStorageBackendConfig(backendType, req.Config)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	err = sunbeam.UpdateStorageBackend(r.Context(), s, backendName, req.Type, req.Principal, req.ModelUUID, req.Config, expectedRevision)
 	if err != nil {
+		var statusErr api.StatusError
+		if errors.As(err, &statusErr) {
+			if statusErr.Status() == http.StatusNotFound {
+				return response.NotFound(err)
+			}
+			if statusErr.Status() == http.StatusPreconditionFailed {
+				return response.PreconditionFailed(err)
+			}
+		}
 		return response.InternalError(err)
 	}
 
 	return response.EmptySyncResponse
 }
+
+// requireIfMatch parses the mandatory If-Match header carrying the revision
+// the client last observed via ETag.
+func requireIfMatch(r *http.Request) (int64, error) {
+	ifMatch := strings.Trim(r.Header.Get("If-Match"), `"`)
+	if ifMatch == "" {
+		return 0, fmt.Errorf("If-Match header is required")
+	}
+
+	revision, err := strconv.ParseInt(ifMatch, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid If-Match header %q: %w", ifMatch, err)
+	}
+
+	return revision, nil
+}
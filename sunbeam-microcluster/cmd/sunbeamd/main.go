@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/canonical/microcluster/v2/microcluster"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/state"
+
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/api"
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/daemon"
+)
+
+// stateDir is where the daemon keeps its cluster database, certificates and
+// control socket.
+const stateDir = "/var/snap/openstack/common/state"
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run builds the microcluster app and starts the daemon, serving the
+// sunbeam-microcluster REST API. daemon.Hooks wires OnStart to apply
+// pending schema migrations before /1.0/* is served, and is extended here
+// to also execute whichever sunbeamd subcommand (migrate, rotate-keys) the
+// operator invoked, now that the daemon state is fully initialized.
+func run() error {
+	ctx := context.Background()
+
+	app, err := microcluster.App(microcluster.Args{StateDir: stateDir})
+	if err != nil {
+		return fmt.Errorf("failed to initialize microcluster app: %w", err)
+	}
+
+	hooks := daemon.Hooks()
+	hooks.OnStart = runSubcommandAfter(hooks.OnStart)
+
+	return app.Start(ctx, microcluster.DaemonArgs{
+		Version: "1.0",
+		Servers: []rest.Server{
+			{
+				CoreAPI: true,
+				Resources: []rest.Resources{
+					{PathPrefix: "1.0", Endpoints: api.Endpoints},
+				},
+			},
+		},
+		Hooks: hooks,
+	})
+}
+
+// runSubcommandAfter wraps onStart so that, once it has run (applying
+// pending migrations), the sunbeamd root command tree executes against the
+// resulting daemon state. With no subcommand on the command line this is a
+// no-op and the daemon falls through to serving requests as normal.
+func runSubcommandAfter(onStart func(context.Context, state.State) error) func(context.Context, state.State) error {
+	return func(ctx context.Context, s state.State) error {
+		if onStart != nil {
+			if err := onStart(ctx, s); err != nil {
+				return err
+			}
+		}
+
+		if len(os.Args) < 2 {
+			return nil
+		}
+
+		return NewRootCmd(s).ExecuteContext(ctx)
+	}
+}
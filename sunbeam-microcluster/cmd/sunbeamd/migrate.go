@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/canonical/microcluster/v2/state"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/database/migrations"
+)
+
+// NewMigrateCmd returns the `sunbeamd migrate` command tree, covering
+// `status`, `up` and `down N`.
+func NewMigrateCmd(s state.State) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Inspect and apply database schema migrations",
+	}
+
+	cmd.AddCommand(newMigrateStatusCmd(s))
+	cmd.AddCommand(newMigrateUpCmd(s))
+	cmd.AddCommand(newMigrateDownCmd(s))
+
+	return cmd
+}
+
+func newMigrateStatusCmd(s state.State) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show which migrations are applied and which are pending",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return s.Database().Transaction(cmd.Context(), func(ctx context.Context, tx *sql.Tx) error {
+				statuses, err := migrations.CurrentStatus(ctx, tx)
+				if err != nil {
+					return err
+				}
+
+				for _, status := range statuses {
+					label := "pending"
+					if status.Applied {
+						label = "applied"
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "%4d  %-8s  %s\n", status.Migration.Version, label, status.Migration.Description)
+				}
+
+				return nil
+			})
+		},
+	}
+}
+
+func newMigrateUpCmd(s state.State) *cobra.Command {
+	return &cobra.Command{
+		Use:   "up [N]",
+		Short: "Apply pending migrations, optionally stopping at version N",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := parseTargetVersion(args)
+			if err != nil {
+				return err
+			}
+
+			return s.Database().Transaction(cmd.Context(), func(ctx context.Context, tx *sql.Tx) error {
+				return migrations.Up(ctx, tx, target)
+			})
+		},
+	}
+}
+
+func newMigrateDownCmd(s state.State) *cobra.Command {
+	return &cobra.Command{
+		Use:   "down N",
+		Short: "Revert applied migrations down to version N",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := parseTargetVersion(args)
+			if err != nil {
+				return err
+			}
+
+			return s.Database().Transaction(cmd.Context(), func(ctx context.Context, tx *sql.Tx) error {
+				return migrations.Down(ctx, tx, target)
+			})
+		},
+	}
+}
+
+func parseTargetVersion(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+
+	target, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid migration version %q: %w", args[0], err)
+	}
+
+	return target, nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/canonical/microcluster/v2/state"
+	"github.com/spf13/cobra"
+)
+
+// NewRootCmd returns the sunbeamd root command tree, the attachment point
+// for daemon-state-aware subcommands such as migrate and rotate-keys.
+func NewRootCmd(s state.State) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sunbeamd",
+		Short: "sunbeam-microcluster daemon control",
+	}
+
+	cmd.AddCommand(NewMigrateCmd(s))
+	cmd.AddCommand(NewRotateKeysCmd(s))
+
+	return cmd
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/canonical/microcluster/v2/state"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/sunbeam"
+)
+
+// NewRotateKeysCmd returns the `sunbeamd rotate-keys` command, which
+// re-encrypts every storage backend's sensitive config fields under a
+// freshly generated data-encryption key.
+func NewRotateKeysCmd(s state.State) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-keys",
+		Short: "Rotate the storage backend data-encryption key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sunbeam.RotateStorageBackendKeys(cmd.Context(), s)
+		},
+	}
+}
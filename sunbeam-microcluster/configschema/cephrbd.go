@@ -0,0 +1,33 @@
+package configschema
+
+import "fmt"
+
+func init() {
+	Register("ceph-rbd", func() Config { return &CephRBDConfig{} })
+}
+
+// CephRBDConfig is the configuration schema for a "ceph-rbd" storage backend.
+type CephRBDConfig struct {
+	MonHost string `json:"mon-host"`
+	Pool    string `json:"pool"`
+	User    string `json:"user"`
+	Keyring string `json:"keyring" sensitive:"true"`
+}
+
+// Validate implements Config.
+func (c *CephRBDConfig) Validate() error {
+	if c.MonHost == "" {
+		return fmt.Errorf("mon-host is required")
+	}
+	if c.Pool == "" {
+		return fmt.Errorf("pool is required")
+	}
+	if c.User == "" {
+		return fmt.Errorf("user is required")
+	}
+	if c.Keyring == "" {
+		return fmt.Errorf("keyring is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package configschema
+
+import "fmt"
+
+func init() {
+	Register("cinder-volume", func() Config { return &CinderVolumeConfig{} })
+}
+
+// CinderVolumeConfig is the configuration schema for a "cinder-volume"
+// storage backend.
+type CinderVolumeConfig struct {
+	AuthURL    string `json:"auth-url"`
+	VolumeType string `json:"volume-type,omitempty"`
+	Username   string `json:"username"`
+	Password   string `json:"password" sensitive:"true"`
+	ProjectID  string `json:"project-id"`
+}
+
+// Validate implements Config.
+func (c *CinderVolumeConfig) Validate() error {
+	if c.AuthURL == "" {
+		return fmt.Errorf("auth-url is required")
+	}
+	if c.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	if c.Password == "" {
+		return fmt.Errorf("password is required")
+	}
+	if c.ProjectID == "" {
+		return fmt.Errorf("project-id is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Package configschema provides typed, per-backend-type configuration
+// schemas for storage backends. Each backend Type (e.g. "s3", "ceph-rbd",
+// "nfs", "cinder-volume") registers a Go struct describing its accepted
+// configuration, so the API can unmarshal and validate client-supplied
+// config before it ever reaches the database.
+package configschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Config is implemented by every per-backend-type configuration schema.
+// Validate should enforce required fields and any cross-field invariants.
+type Config interface {
+	// Validate checks that the config is complete and well-formed.
+	Validate() error
+}
+
+// registry maps a storage backend Type to a constructor for its Config.
+var registry = map[string]func() Config{}
+
+// Register associates a backend Type with a factory for its Config schema.
+// It is expected to be called from package init functions.
+func Register(backendType string, factory func() Config) {
+	registry[backendType] = factory
+}
+
+// Unmarshal decodes raw into the Config schema registered for backendType,
+// rejecting unknown fields, and validates the result. It returns an error
+// if backendType has no registered schema.
+func Unmarshal(backendType string, raw []byte) (Config, error) {
+	factory, ok := registry[backendType]
+	if !ok {
+		return nil, fmt.Errorf("unknown storage backend type %q", backendType)
+	}
+
+	cfg := factory()
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config for storage backend type %q: %w", backendType, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config for storage backend type %q: %w", backendType, err)
+	}
+
+	return cfg, nil
+}
+
+// Marshal normalizes cfg back into its canonical JSON representation, ready
+// for persistence.
+func Marshal(cfg Config) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal storage backend config: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,26 @@
+package configschema
+
+import "fmt"
+
+func init() {
+	Register("nfs", func() Config { return &NFSConfig{} })
+}
+
+// NFSConfig is the configuration schema for a "nfs" storage backend.
+type NFSConfig struct {
+	Server  string `json:"server"`
+	Path    string `json:"path"`
+	Options string `json:"options,omitempty"`
+}
+
+// Validate implements Config.
+func (c *NFSConfig) Validate() error {
+	if c.Server == "" {
+		return fmt.Errorf("server is required")
+	}
+	if c.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+
+	return nil
+}
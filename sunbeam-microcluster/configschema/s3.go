@@ -0,0 +1,35 @@
+package configschema
+
+import "fmt"
+
+func init() {
+	Register("s3", func() Config { return &S3Config{} })
+}
+
+// S3Config is the configuration schema for a "s3" storage backend.
+type S3Config struct {
+	Endpoint  string `json:"endpoint"`
+	Region    string `json:"region,omitempty"`
+	Bucket    string `json:"bucket"`
+	AccessKey string `json:"access-key" sensitive:"true"`
+	SecretKey string `json:"secret-key" sensitive:"true"`
+	URLPrefix string `json:"url-prefix,omitempty"`
+}
+
+// Validate implements Config.
+func (c *S3Config) Validate() error {
+	if c.Endpoint == "" {
+		return fmt.Errorf("endpoint is required")
+	}
+	if c.Bucket == "" {
+		return fmt.Errorf("bucket is required")
+	}
+	if c.AccessKey == "" {
+		return fmt.Errorf("access-key is required")
+	}
+	if c.SecretKey == "" {
+		return fmt.Errorf("secret-key is required")
+	}
+
+	return nil
+}
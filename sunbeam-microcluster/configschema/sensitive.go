@@ -0,0 +1,40 @@
+package configschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WalkSensitive calls fn for every non-empty string field of cfg tagged
+// `sensitive:"true"`, replacing the field's value with whatever fn returns;
+// empty fields are left as they are. It is used to apply
+// encryption/decryption to only the fields that actually carry secrets,
+// leaving the rest of the config queryable in plain text.
+func WalkSensitive(cfg Config, fn func(value string) (string, error)) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("configschema: WalkSensitive requires a non-nil pointer to a Config")
+	}
+	v = v.Elem()
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("sensitive") != "true" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() != reflect.String || fv.String() == "" {
+			continue
+		}
+
+		newValue, err := fn(fv.String())
+		if err != nil {
+			return fmt.Errorf("field %q: %w", field.Name, err)
+		}
+		fv.SetString(newValue)
+	}
+
+	return nil
+}
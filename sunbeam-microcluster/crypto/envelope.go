@@ -0,0 +1,139 @@
+// Package crypto implements envelope encryption for sensitive fields
+// persisted by sunbeam-microcluster: a per-cluster data-encryption key
+// (DEK) encrypts the data itself, while the DEK is in turn wrapped by a
+// key-encryption key (KEK) kept outside the database. The wrapped DEK
+// itself is stored in the replicated cluster database (see
+// database.GetWrappedDEK/SetWrappedDEK) so every member converges on the
+// same key; only the KEK is provisioned out-of-band, identically on every
+// node.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// DefaultKEKPath is where the key-encryption key is read from. It is
+	// expected to be provisioned out-of-band, identically on every cluster
+	// member, e.g. by the snap install hook, and never stored in the
+	// cluster database.
+	DefaultKEKPath = "/var/snap/openstack/common/keys/kek"
+
+	keySize   = 32 // AES-256
+	nonceSize = 12 // standard GCM nonce size
+)
+
+// LoadKEK reads the key-encryption key from path. The file must contain
+// exactly keySize raw bytes.
+func LoadKEK(path string) ([]byte, error) {
+	kek, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key-encryption key from %s: %w", path, err)
+	}
+
+	if len(kek) != keySize {
+		return nil, fmt.Errorf("key-encryption key at %s must be %d bytes, got %d", path, keySize, len(kek))
+	}
+
+	return kek, nil
+}
+
+// GenerateDEK creates a new random data-encryption key.
+func GenerateDEK() ([]byte, error) {
+	dek := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, fmt.Errorf("failed to generate data-encryption key: %w", err)
+	}
+
+	return dek, nil
+}
+
+// Wrap encrypts dek with kek, ready to be persisted as the cluster's
+// wrapped data-encryption key.
+func Wrap(dek []byte, kek []byte) ([]byte, error) {
+	wrapped, err := seal(dek, kek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data-encryption key: %w", err)
+	}
+
+	return wrapped, nil
+}
+
+// Unwrap reverses Wrap.
+func Unwrap(wrapped []byte, kek []byte) ([]byte, error) {
+	dek, err := open(wrapped, kek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data-encryption key: %w", err)
+	}
+
+	return dek, nil
+}
+
+// EncryptString encrypts plaintext with dek and returns a base64-encoded
+// "nonce || ciphertext" blob suitable for storing in a text column.
+func EncryptString(plaintext string, dek []byte) (string, error) {
+	sealed, err := seal([]byte(plaintext), dek)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt value: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptString reverses EncryptString.
+func DecryptString(encoded string, dek []byte) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+
+	plaintext, err := open(sealed, dek)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+func seal(plaintext []byte, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(sealed []byte, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:nonceSize], sealed[nonceSize:]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,24 @@
+// Package daemon assembles the microcluster lifecycle hooks for
+// sunbeam-microcluster.
+package daemon
+
+import (
+	"context"
+
+	"github.com/canonical/microcluster/v2/state"
+
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/database"
+)
+
+// Hooks returns the microcluster state.Hooks for sunbeam-microcluster. Pass
+// the result to microcluster.App.Start so OnStart runs pending schema
+// migrations before the daemon serves /1.0/* requests - this is what keeps
+// a newly elected leader, or a member resuming after an upgrade, from
+// serving requests against a stale schema.
+func Hooks() *state.Hooks {
+	return &state.Hooks{
+		OnStart: func(ctx context.Context, s state.State) error {
+			return database.Migrate(ctx, s)
+		},
+	}
+}
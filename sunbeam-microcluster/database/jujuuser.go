@@ -0,0 +1,34 @@
+package database
+
+//go:generate -command mapper lxd-generate db mapper -t jujuuser.mapper.go
+//go:generate mapper reset
+//
+//go:generate mapper stmt -d github.com/canonical/microcluster/v2/cluster -e JujuUser objects table=jujuusers
+//go:generate mapper stmt -d github.com/canonical/microcluster/v2/cluster -e JujuUser objects-by-Username table=jujuusers
+//go:generate mapper stmt -d github.com/canonical/microcluster/v2/cluster -e JujuUser id table=jujuusers
+//go:generate mapper stmt -d github.com/canonical/microcluster/v2/cluster -e JujuUser create table=jujuusers
+//go:generate mapper stmt -d github.com/canonical/microcluster/v2/cluster -e JujuUser delete-by-Username table=jujuusers
+//go:generate mapper stmt -d github.com/canonical/microcluster/v2/cluster -e JujuUser update table=jujuusers
+//
+//go:generate mapper method -i -d github.com/canonical/microcluster/v2/cluster -e JujuUser GetMany
+//go:generate mapper method -i -d github.com/canonical/microcluster/v2/cluster -e JujuUser GetOne
+//go:generate mapper method -i -d github.com/canonical/microcluster/v2/cluster -e JujuUser ID
+//go:generate mapper method -i -d github.com/canonical/microcluster/v2/cluster -e JujuUser Exists
+//go:generate mapper method -i -d github.com/canonical/microcluster/v2/cluster -e JujuUser Create
+//go:generate mapper method -i -d github.com/canonical/microcluster/v2/cluster -e JujuUser DeleteOne-by-Username
+//go:generate mapper method -i -d github.com/canonical/microcluster/v2/cluster -e JujuUser Update
+
+// JujuUser is used to track Juju user credentials.
+type JujuUser struct {
+	ID       int
+	Username string `db:"primary=yes"`
+	Token    string
+	// Revision is incremented on every update and used for optimistic
+	// concurrency control via the ETag/If-Match headers.
+	Revision int64
+}
+
+// JujuUserFilter is a required struct for use with lxd-generate. It is used for filtering fields on database fetches.
+type JujuUserFilter struct {
+	Username *string
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// GetWrappedDEK returns the cluster's envelope-wrapped data-encryption key
+// as currently agreed through the replicated database, or ok=false if no
+// member has bootstrapped one yet.
+func GetWrappedDEK(ctx context.Context, tx *sql.Tx) (wrapped []byte, ok bool, err error) {
+	row := tx.QueryRowContext(ctx, "SELECT wrapped_dek FROM encryption_keys WHERE id = 1")
+
+	err = row.Scan(&wrapped)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch wrapped data-encryption key: %w", err)
+	}
+
+	return wrapped, true, nil
+}
+
+// CreateWrappedDEK persists the cluster's envelope-wrapped data-encryption
+// key. Callers must call it inside the same transaction in which
+// GetWrappedDEK was observed to return ok=false, so dqlite's single-writer
+// serialization prevents two members from bootstrapping different keys.
+func CreateWrappedDEK(ctx context.Context, tx *sql.Tx, wrapped []byte) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO encryption_keys (id, wrapped_dek) VALUES (1, ?)", wrapped)
+	if err != nil {
+		return fmt.Errorf("failed to persist wrapped data-encryption key: %w", err)
+	}
+
+	return nil
+}
+
+// SetWrappedDEK replaces the cluster's envelope-wrapped data-encryption key,
+// used when rotating to a new key.
+func SetWrappedDEK(ctx context.Context, tx *sql.Tx, wrapped []byte) error {
+	_, err := tx.ExecContext(ctx, "UPDATE encryption_keys SET wrapped_dek = ? WHERE id = 1", wrapped)
+	if err != nil {
+		return fmt.Errorf("failed to update wrapped data-encryption key: %w", err)
+	}
+
+	return nil
+}
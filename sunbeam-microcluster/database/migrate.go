@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/canonical/microcluster/v2/state"
+
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/database/migrations"
+)
+
+// Migrate runs every pending schema migration inside a single transaction.
+// It is invoked from the daemon's OnStart hook (see daemon.Hooks) so a newly
+// elected leader brings the schema up to date before serving /1.0/* requests.
+func Migrate(ctx context.Context, s state.State) error {
+	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return migrations.Up(ctx, tx, 0)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply pending schema migrations: %w", err)
+	}
+
+	return nil
+}
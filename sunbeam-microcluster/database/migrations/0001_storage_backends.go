@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	Register(Migration{
+		Version:     1,
+		Description: "create storage_backends table",
+		Up:          up0001StorageBackends,
+		Down:        down0001StorageBackends,
+	})
+}
+
+func up0001StorageBackends(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS storage_backends (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	type TEXT NOT NULL,
+	config TEXT NOT NULL,
+	principal TEXT NOT NULL,
+	model_uuid TEXT NOT NULL
+)`)
+
+	return err
+}
+
+func down0001StorageBackends(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE storage_backends")
+
+	return err
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	Register(Migration{
+		Version:     2,
+		Description: "create jujuusers table",
+		Up:          up0002JujuUsers,
+		Down:        down0002JujuUsers,
+	})
+}
+
+func up0002JujuUsers(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS jujuusers (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	username TEXT NOT NULL UNIQUE,
+	token TEXT NOT NULL
+)`)
+
+	return err
+}
+
+func down0002JujuUsers(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE jujuusers")
+
+	return err
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	Register(Migration{
+		Version:     3,
+		Description: "add revision column to storage_backends",
+		Up:          up0003StorageBackendsRevision,
+		Down:        down0003StorageBackendsRevision,
+	})
+}
+
+func up0003StorageBackendsRevision(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "ALTER TABLE storage_backends ADD COLUMN revision INTEGER NOT NULL DEFAULT 1")
+
+	return err
+}
+
+func down0003StorageBackendsRevision(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "ALTER TABLE storage_backends DROP COLUMN revision")
+
+	return err
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	Register(Migration{
+		Version:     4,
+		Description: "add revision column to jujuusers",
+		Up:          up0004JujuUsersRevision,
+		Down:        down0004JujuUsersRevision,
+	})
+}
+
+func up0004JujuUsersRevision(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "ALTER TABLE jujuusers ADD COLUMN revision INTEGER NOT NULL DEFAULT 1")
+
+	return err
+}
+
+func down0004JujuUsersRevision(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "ALTER TABLE jujuusers DROP COLUMN revision")
+
+	return err
+}
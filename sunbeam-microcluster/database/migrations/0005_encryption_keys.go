@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	Register(Migration{
+		Version:     5,
+		Description: "create encryption_keys table",
+		Up:          up0005EncryptionKeys,
+		Down:        down0005EncryptionKeys,
+	})
+}
+
+func up0005EncryptionKeys(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS encryption_keys (
+	id INTEGER PRIMARY KEY CHECK (id = 1),
+	wrapped_dek BLOB NOT NULL
+)`)
+
+	return err
+}
+
+func down0005EncryptionKeys(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE encryption_keys")
+
+	return err
+}
@@ -0,0 +1,176 @@
+// Package migrations implements an ordered, versioned schema-migration
+// system for the microcluster database. Migrations are registered in order
+// of their Version and applied inside a single transaction, with progress
+// tracked in the applied_migrations bookkeeping table so a newly elected
+// leader only ever runs what is still pending.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Migration describes a single, numbered schema change. Up must be
+// idempotent-safe to run once; Down must fully reverse it.
+type Migration struct {
+	// Version uniquely and monotonically identifies this migration.
+	Version int
+	// Description is a short human-readable summary, surfaced by `migrate status`.
+	Description string
+	// Up applies the migration.
+	Up func(ctx context.Context, tx *sql.Tx) error
+	// Down reverses the migration.
+	Down func(ctx context.Context, tx *sql.Tx) error
+}
+
+var registry []Migration
+
+// Register adds a migration to the ordered set applied by Up/Down. It is
+// expected to be called from package init functions, one per migration file.
+func Register(m Migration) {
+	registry = append(registry, m)
+}
+
+// All returns every registered migration, ordered by Version.
+func All() []Migration {
+	ordered := make([]Migration, len(registry))
+	copy(ordered, registry)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Version < ordered[j].Version })
+
+	return ordered
+}
+
+const createAppliedMigrationsTable = `
+CREATE TABLE IF NOT EXISTS applied_migrations (
+	version INTEGER NOT NULL PRIMARY KEY,
+	applied_at TEXT NOT NULL
+)`
+
+// ensureBookkeepingTable creates the applied_migrations table if it does not exist.
+func ensureBookkeepingTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, createAppliedMigrationsTable)
+	if err != nil {
+		return fmt.Errorf("failed to create applied_migrations table: %w", err)
+	}
+
+	return nil
+}
+
+// Applied returns the set of migration versions already recorded as applied.
+func Applied(ctx context.Context, tx *sql.Tx) (map[int]bool, error) {
+	if err := ensureBookkeepingTable(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT version FROM applied_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+// Pending returns the registered migrations that have not yet been applied, in order.
+func Pending(ctx context.Context, tx *sql.Tx) ([]Migration, error) {
+	applied, err := Applied(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, m := range All() {
+		if !applied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies every pending migration up to and including target, in order.
+// A target of 0 applies everything pending.
+func Up(ctx context.Context, tx *sql.Tx, target int) error {
+	pending, err := Pending(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		if target != 0 && m.Version > target {
+			break
+		}
+
+		if err := m.Up(ctx, tx); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+
+		_, err := tx.ExecContext(ctx, "INSERT INTO applied_migrations (version, applied_at) VALUES (?, ?)", m.Version, time.Now().UTC().Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("failed to record migration %d as applied: %w", m.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// Down reverses every applied migration with a version greater than target,
+// from the highest version down.
+func Down(ctx context.Context, tx *sql.Tx, target int) error {
+	applied, err := Applied(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	all := All()
+	for i := len(all) - 1; i >= 0; i-- {
+		m := all[i]
+		if !applied[m.Version] || m.Version <= target {
+			continue
+		}
+
+		if err := m.Down(ctx, tx); err != nil {
+			return fmt.Errorf("reverting migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+
+		_, err := tx.ExecContext(ctx, "DELETE FROM applied_migrations WHERE version = ?", m.Version)
+		if err != nil {
+			return fmt.Errorf("failed to unmark migration %d as applied: %w", m.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// Status reports every registered migration alongside whether it is currently applied.
+type Status struct {
+	Migration Migration
+	Applied   bool
+}
+
+// CurrentStatus returns the status of every registered migration, in order.
+func CurrentStatus(ctx context.Context, tx *sql.Tx) ([]Status, error) {
+	applied, err := Applied(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, m := range All() {
+		statuses = append(statuses, Status{Migration: m, Applied: applied[m.Version]})
+	}
+
+	return statuses, nil
+}
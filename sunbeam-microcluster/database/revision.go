@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ErrRevisionMismatch is returned by the *IfRevision functions when the
+// row's current revision does not match the caller's expected revision,
+// i.e. the row was concurrently modified since it was last read.
+var ErrRevisionMismatch = fmt.Errorf("revision mismatch")
+
+// UpdateStorageBackendIfRevision updates the storage backend named name
+// with object's fields and bumps its revision, but only if its current
+// revision equals expectedRevision.
+func UpdateStorageBackendIfRevision(ctx context.Context, tx *sql.Tx, name string, expectedRevision int64, object StorageBackend) error {
+	result, err := tx.ExecContext(ctx, `
+UPDATE storage_backends
+SET type = ?, config = ?, principal = ?, model_uuid = ?, revision = revision + 1
+WHERE name = ? AND revision = ?`,
+		object.Type, object.Config, object.Principal, object.ModelUUID, name, expectedRevision)
+	if err != nil {
+		return fmt.Errorf("failed to update storage backend %q: %w", name, err)
+	}
+
+	return requireRowAffected(result, "storage backend", name)
+}
+
+// DeleteStorageBackendIfRevision deletes the storage backend named name, but
+// only if its current revision equals expectedRevision.
+func DeleteStorageBackendIfRevision(ctx context.Context, tx *sql.Tx, name string, expectedRevision int64) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM storage_backends WHERE name = ? AND revision = ?", name, expectedRevision)
+	if err != nil {
+		return fmt.Errorf("failed to delete storage backend %q: %w", name, err)
+	}
+
+	return requireRowAffected(result, "storage backend", name)
+}
+
+// UpdateJujuUserIfRevision updates the juju user named username with
+// object's fields and bumps its revision, but only if its current revision
+// equals expectedRevision.
+func UpdateJujuUserIfRevision(ctx context.Context, tx *sql.Tx, username string, expectedRevision int64, object JujuUser) error {
+	result, err := tx.ExecContext(ctx, `
+UPDATE jujuusers
+SET token = ?, revision = revision + 1
+WHERE username = ? AND revision = ?`,
+		object.Token, username, expectedRevision)
+	if err != nil {
+		return fmt.Errorf("failed to update juju user %q: %w", username, err)
+	}
+
+	return requireRowAffected(result, "juju user", username)
+}
+
+// DeleteJujuUserIfRevision deletes the juju user named username, but only if
+// its current revision equals expectedRevision.
+func DeleteJujuUserIfRevision(ctx context.Context, tx *sql.Tx, username string, expectedRevision int64) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM jujuusers WHERE username = ? AND revision = ?", username, expectedRevision)
+	if err != nil {
+		return fmt.Errorf("failed to delete juju user %q: %w", username, err)
+	}
+
+	return requireRowAffected(result, "juju user", username)
+}
+
+// requireRowAffected returns ErrRevisionMismatch if result affected no rows,
+// which for the conditional statements above means the row's revision had
+// already moved on.
+func requireRowAffected(result sql.Result, kind string, name string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected for %s %q: %w", kind, name, err)
+	}
+
+	if rows == 0 {
+		return ErrRevisionMismatch
+	}
+
+	return nil
+}
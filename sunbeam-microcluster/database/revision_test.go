@@ -0,0 +1,114 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/database"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open in-memory database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// sqlite3 allows only one writer at a time; force the pool down to a
+	// single connection so both goroutines below serialize on it the same
+	// way dqlite would serialize on the real cluster leader.
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+CREATE TABLE storage_backends (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	type TEXT NOT NULL,
+	config TEXT NOT NULL,
+	principal TEXT NOT NULL,
+	model_uuid TEXT NOT NULL,
+	revision INTEGER NOT NULL DEFAULT 1
+)`)
+	if err != nil {
+		t.Fatalf("failed to create storage_backends table: %v", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO storage_backends (name, type, config, principal, model_uuid, revision) VALUES ('s3-backend', 's3', '{}', 'cinder', 'model-uuid', 1)`)
+	if err != nil {
+		t.Fatalf("failed to seed storage_backends table: %v", err)
+	}
+
+	return db
+}
+
+// TestUpdateStorageBackendIfRevision_RacingPUTs simulates two concurrent
+// operators reading the same revision and racing to PUT an update: exactly
+// one must succeed and the other must see ErrRevisionMismatch.
+func TestUpdateStorageBackendIfRevision_RacingPUTs(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	const expectedRevision = 1
+
+	var wg sync.WaitGroup
+	results := make([]error, 2)
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				results[i] = err
+				return
+			}
+
+			results[i] = database.UpdateStorageBackendIfRevision(ctx, tx, "s3-backend", expectedRevision, database.StorageBackend{
+				Name:      "s3-backend",
+				Type:      "s3",
+				Config:    "{}",
+				Principal: "cinder",
+				ModelUUID: "model-uuid",
+			})
+
+			if results[i] == nil {
+				results[i] = tx.Commit()
+			} else {
+				tx.Rollback()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	successes, conflicts := 0, 0
+	for _, err := range results {
+		switch {
+		case err == nil:
+			successes++
+		case err == database.ErrRevisionMismatch:
+			conflicts++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if successes != 1 || conflicts != 1 {
+		t.Fatalf("expected exactly one success and one conflict, got %d successes and %d conflicts", successes, conflicts)
+	}
+
+	var revision int64
+	if err := db.QueryRow("SELECT revision FROM storage_backends WHERE name = 's3-backend'").Scan(&revision); err != nil {
+		t.Fatalf("failed to read final revision: %v", err)
+	}
+	if revision != expectedRevision+1 {
+		t.Fatalf("expected final revision %d, got %d", expectedRevision+1, revision)
+	}
+}
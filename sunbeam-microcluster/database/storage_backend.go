@@ -29,6 +29,9 @@ type StorageBackend struct {
 	Config    string
 	Principal string
 	ModelUUID string
+	// Revision is incremented on every update and used for optimistic
+	// concurrency control via the ETag/If-Match headers.
+	Revision int64
 }
 
 // StorageBackendFilter is a required struct for use with lxd-generate. It is used for filtering fields on database fetches.
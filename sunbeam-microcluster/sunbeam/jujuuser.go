@@ -0,0 +1,131 @@
+package sunbeam
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v2/state"
+
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/api/apitypes"
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/database"
+)
+
+// ListJujuUsers returns the juju users matching filter. A zero-value filter
+// returns every juju user.
+func ListJujuUsers(ctx context.Context, s state.State, filter apitypes.JujuUserFilter) (apitypes.JujuUsers, error) {
+	users := apitypes.JujuUsers{}
+
+	dbFilter := database.JujuUserFilter{
+		Username: filter.Username,
+	}
+
+	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		records, err := database.GetJujuUsers(ctx, tx, dbFilter)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch juju users: %w", err)
+		}
+
+		for _, record := range records {
+			users = append(users, apitypes.JujuUser{
+				Username: record.Username,
+				Token:    record.Token,
+				Revision: record.Revision,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetJujuUser returns a JujuUser with the given username
+func GetJujuUser(ctx context.Context, s state.State, username string) (apitypes.JujuUser, error) {
+	user := apitypes.JujuUser{}
+	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		record, err := database.GetJujuUser(ctx, tx, username)
+		if err != nil {
+			return err
+		}
+
+		user.Username = record.Username
+		user.Token = record.Token
+		user.Revision = record.Revision
+
+		return nil
+	})
+	if err != nil {
+		return apitypes.JujuUser{}, err
+	}
+	return user, nil
+}
+
+// AddJujuUser adds a juju user to the database
+func AddJujuUser(ctx context.Context, s state.State, username string, token string) error {
+	return s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := database.CreateJujuUser(ctx, tx, database.JujuUser{
+			Username: username,
+			Token:    token,
+			Revision: 1,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to record juju user: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UpdateJujuUser updates a juju user record in the database. expectedRevision
+// must match the row's current revision, as last observed via the ETag
+// header on a GET; otherwise a 412 Precondition Failed api.StatusError is
+// returned and the update is not applied.
+func UpdateJujuUser(ctx context.Context, s state.State, username string, token string, expectedRevision int64) error {
+	return s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		user, err := database.GetJujuUser(ctx, tx, username)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve juju user details: %w", err)
+		}
+
+		if token == "" {
+			token = user.Token
+		}
+
+		err = database.UpdateJujuUserIfRevision(ctx, tx, username, expectedRevision, database.JujuUser{Username: username, Token: token})
+		if errors.Is(err, database.ErrRevisionMismatch) {
+			return api.StatusErrorf(http.StatusPreconditionFailed, "juju user %q was concurrently modified", username)
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to update record juju user: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteJujuUser deletes a juju user from database. expectedRevision must
+// match the row's current revision; otherwise a 412 Precondition Failed
+// api.StatusError is returned and nothing is deleted. A nonexistent username
+// yields a 404 api.StatusError rather than a 412.
+func DeleteJujuUser(ctx context.Context, s state.State, username string, expectedRevision int64) error {
+	return s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := database.GetJujuUser(ctx, tx, username)
+		if err != nil {
+			return err
+		}
+
+		err = database.DeleteJujuUserIfRevision(ctx, tx, username, expectedRevision)
+		if errors.Is(err, database.ErrRevisionMismatch) {
+			return api.StatusErrorf(http.StatusPreconditionFailed, "juju user %q was concurrently modified", username)
+		}
+
+		return err
+	})
+}
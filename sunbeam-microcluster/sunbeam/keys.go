@@ -0,0 +1,153 @@
+package sunbeam
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/canonical/microcluster/v2/state"
+
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/crypto"
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/database"
+)
+
+var (
+	dekMu         sync.RWMutex
+	cachedWrapped []byte
+	cachedDEK     []byte
+)
+
+// dataEncryptionKey returns the cluster's data-encryption key. The
+// envelope-wrapped key is agreed through the replicated database (see
+// database.GetWrappedDEK/CreateWrappedDEK), not a per-node local file: the
+// first member to observe no wrapped key present bootstraps one inside a
+// database transaction, which dqlite's single-writer serialization ensures
+// only one member can win, so every member - including ones that join later
+// or have lost local state - converges on the same key instead of each
+// minting its own.
+//
+// The wrapped key read from the database is compared against cachedWrapped
+// on every call, not just when the cache is empty, so a rotation performed
+// by another cluster member is picked up on this member's very next use
+// instead of only after a restart.
+func dataEncryptionKey(ctx context.Context, s state.State) ([]byte, error) {
+	kek, err := crypto.LoadKEK(crypto.DefaultKEKPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key-encryption key: %w", err)
+	}
+
+	var dek []byte
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		wrapped, ok, err := database.GetWrappedDEK(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			dek, err = crypto.GenerateDEK()
+			if err != nil {
+				return err
+			}
+
+			wrapped, err = crypto.Wrap(dek, kek)
+			if err != nil {
+				return err
+			}
+
+			if err := database.CreateWrappedDEK(ctx, tx, wrapped); err != nil {
+				return err
+			}
+
+			cacheDEK(wrapped, dek)
+			return nil
+		}
+
+		dekMu.RLock()
+		cacheHit := cachedWrapped != nil && bytes.Equal(wrapped, cachedWrapped)
+		if cacheHit {
+			dek = cachedDEK
+		}
+		dekMu.RUnlock()
+		if cacheHit {
+			return nil
+		}
+
+		dek, err = crypto.Unwrap(wrapped, kek)
+		if err != nil {
+			return err
+		}
+
+		cacheDEK(wrapped, dek)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to bootstrap data-encryption key: %w", err)
+	}
+
+	return dek, nil
+}
+
+func cacheDEK(wrapped []byte, dek []byte) {
+	dekMu.Lock()
+	cachedWrapped = wrapped
+	cachedDEK = dek
+	dekMu.Unlock()
+}
+
+// RotateStorageBackendKeys re-encrypts every storage backend's sensitive
+// config fields under a freshly generated data-encryption key and persists
+// the re-encrypted rows together with the newly wrapped key in a single
+// database transaction, so a crash can never leave rows encrypted under a
+// key that was never durably saved. It backs the `sunbeamd rotate-keys` CLI
+// path.
+func RotateStorageBackendKeys(ctx context.Context, s state.State) error {
+	oldDEK, err := dataEncryptionKey(ctx, s)
+	if err != nil {
+		return fmt.Errorf("failed to load current data-encryption key: %w", err)
+	}
+
+	kek, err := crypto.LoadKEK(crypto.DefaultKEKPath)
+	if err != nil {
+		return fmt.Errorf("failed to load key-encryption key: %w", err)
+	}
+
+	newDEK, err := crypto.GenerateDEK()
+	if err != nil {
+		return fmt.Errorf("failed to generate new data-encryption key: %w", err)
+	}
+
+	wrappedNewDEK, err := crypto.Wrap(newDEK, kek)
+	if err != nil {
+		return fmt.Errorf("failed to wrap new data-encryption key: %w", err)
+	}
+
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		records, err := database.GetStorageBackends(ctx, tx, database.StorageBackendFilter{})
+		if err != nil {
+			return fmt.Errorf("Failed to fetch storage backends: %w", err)
+		}
+
+		for _, record := range records {
+			reencrypted, err := reencryptConfig(record.Type, record.Config, oldDEK, newDEK)
+			if err != nil {
+				return fmt.Errorf("Failed to re-encrypt storage backend %q: %w", record.Name, err)
+			}
+
+			record.Config = reencrypted
+			if err := database.UpdateStorageBackend(ctx, tx, record.Name, record); err != nil {
+				return fmt.Errorf("Failed to update storage backend %q: %w", record.Name, err)
+			}
+		}
+
+		return database.SetWrappedDEK(ctx, tx, wrappedNewDEK)
+	})
+	if err != nil {
+		return err
+	}
+
+	cacheDEK(wrappedNewDEK, newDEK)
+
+	return nil
+}
@@ -3,33 +3,117 @@ package sunbeam
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net/http"
 
+	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/microcluster/v2/state"
 
 	"github.com/canonical/snap-openstack/sunbeam-microcluster/api/apitypes"
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/configschema"
+	"github.com/canonical/snap-openstack/sunbeam-microcluster/crypto"
 	"github.com/canonical/snap-openstack/sunbeam-microcluster/database"
 )
 
-// ListStorageBackends return all the storage backends, filterable by role (Optional)
-func ListStorageBackends(ctx context.Context, s state.State) (apitypes.StorageBackends, error) {
+// encryptConfig encrypts the fields of config tagged `sensitive:"true"` in
+// the schema registered for backendType using dek, returning the config
+// with those fields replaced by their ciphertext. A backendType with no
+// registered schema is an error rather than a silent pass-through, so a
+// registry omission can never leave sensitive fields unencrypted.
+func encryptConfig(backendType string, config string, dek []byte) (string, error) {
+	cfg, err := configschema.Unmarshal(backendType, []byte(config))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse storage backend config: %w", err)
+	}
+
+	err = configschema.WalkSensitive(cfg, func(value string) (string, error) {
+		return crypto.EncryptString(value, dek)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt storage backend config: %w", err)
+	}
+
+	return configschema.Marshal(cfg)
+}
+
+// reencryptConfig decrypts config's sensitive fields with oldDEK and
+// re-encrypts them with newDEK, used during data-encryption key rotation.
+func reencryptConfig(backendType string, config string, oldDEK []byte, newDEK []byte) (string, error) {
+	cfg, err := configschema.Unmarshal(backendType, []byte(config))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse storage backend config: %w", err)
+	}
+
+	err = configschema.WalkSensitive(cfg, func(value string) (string, error) {
+		plaintext, err := crypto.DecryptString(value, oldDEK)
+		if err != nil {
+			return "", err
+		}
+
+		return crypto.EncryptString(plaintext, newDEK)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to re-encrypt storage backend config: %w", err)
+	}
+
+	return configschema.Marshal(cfg)
+}
+
+// decryptConfig reverses encryptConfig.
+func decryptConfig(backendType string, config string, dek []byte) (string, error) {
+	cfg, err := configschema.Unmarshal(backendType, []byte(config))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse storage backend config: %w", err)
+	}
+
+	err = configschema.WalkSensitive(cfg, func(value string) (string, error) {
+		return crypto.DecryptString(value, dek)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt storage backend config: %w", err)
+	}
+
+	return configschema.Marshal(cfg)
+}
+
+// ListStorageBackends returns the storage backends matching filter. A zero-value
+// filter returns every storage backend.
+func ListStorageBackends(ctx context.Context, s state.State, filter apitypes.StorageBackendFilter) (apitypes.StorageBackends, error) {
 	backends := apitypes.StorageBackends{}
 
+	dbFilter := database.StorageBackendFilter{
+		Name:      filter.Name,
+		Type:      filter.Type,
+		Principal: filter.Principal,
+		ModelUUID: filter.ModelUUID,
+	}
+
+	dek, err := dataEncryptionKey(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load data-encryption key: %w", err)
+	}
+
 	// Get the storage backends from the database.
-	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
-		records, err := database.GetStorageBackends(ctx, tx)
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		records, err := database.GetStorageBackends(ctx, tx, dbFilter)
 		if err != nil {
 			return fmt.Errorf("Failed to fetch storage backends: %w", err)
 		}
 
 		for _, backend := range records {
+			config, err := decryptConfig(backend.Type, backend.Config, dek)
+			if err != nil {
+				return fmt.Errorf("Failed to decrypt storage backend %q: %w", backend.Name, err)
+			}
 
 			backends = append(backends, apitypes.StorageBackend{
 				Name:      backend.Name,
 				Type:      backend.Type,
 				Principal: backend.Principal,
 				ModelUUID: backend.ModelUUID,
-				Config:    backend.Config,
+				Config:    config,
+				Revision:  backend.Revision,
 			})
 		}
 
@@ -46,17 +130,29 @@ func ListStorageBackends(ctx context.Context, s state.State) (apitypes.StorageBa
 // GetStorageBackend returns a StorageBackend with the given name
 func GetStorageBackend(ctx context.Context, s state.State, name string) (apitypes.StorageBackend, error) {
 	backend := apitypes.StorageBackend{}
-	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+
+	dek, err := dataEncryptionKey(ctx, s)
+	if err != nil {
+		return apitypes.StorageBackend{}, fmt.Errorf("Failed to load data-encryption key: %w", err)
+	}
+
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
 		record, err := database.GetStorageBackend(ctx, tx, name)
 		if err != nil {
 			return err
 		}
 
+		config, err := decryptConfig(record.Type, record.Config, dek)
+		if err != nil {
+			return fmt.Errorf("Failed to decrypt storage backend %q: %w", record.Name, err)
+		}
+
 		backend.Name = record.Name
 		backend.Type = record.Type
 		backend.Principal = record.Principal
 		backend.ModelUUID = record.ModelUUID
-		backend.Config = record.Config
+		backend.Config = config
+		backend.Revision = record.Revision
 
 		return nil
 	})
@@ -68,6 +164,16 @@ func GetStorageBackend(ctx context.Context, s state.State, name string) (apitype
 
 // AddStorageBackend adds a storage backend to the database
 func AddStorageBackend(ctx context.Context, s state.State, name string, backendType string, principal string, modelUUID string, config string) error {
+	dek, err := dataEncryptionKey(ctx, s)
+	if err != nil {
+		return fmt.Errorf("Failed to load data-encryption key: %w", err)
+	}
+
+	encryptedConfig, err := encryptConfig(backendType, config, dek)
+	if err != nil {
+		return fmt.Errorf("Failed to encrypt storage backend config: %w", err)
+	}
+
 	// Add storage backend to the database.
 	return s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
 		_, err := database.CreateStorageBackend(ctx, tx, database.StorageBackend{
@@ -75,7 +181,8 @@ func AddStorageBackend(ctx context.Context, s state.State, name string, backendT
 			Type:      backendType,
 			Principal: principal,
 			ModelUUID: modelUUID,
-			Config:    config,
+			Config:    encryptedConfig,
+			Revision:  1,
 		})
 		if err != nil {
 			return fmt.Errorf("Failed to record storage backend: %w", err)
@@ -85,10 +192,18 @@ func AddStorageBackend(ctx context.Context, s state.State, name string, backendT
 	})
 }
 
-// UpdateStorageBackend updates a storage backend record in the database
-func UpdateStorageBackend(ctx context.Context, s state.State, name string, backendType string, principal string, modelUUID string, config string) error {
+// UpdateStorageBackend updates a storage backend record in the database.
+// expectedRevision must match the row's current revision, as last observed
+// via the ETag header on a GET; otherwise a 412 Precondition Failed
+// api.StatusError is returned and the update is not applied.
+func UpdateStorageBackend(ctx context.Context, s state.State, name string, backendType string, principal string, modelUUID string, config string, expectedRevision int64) error {
+	dek, err := dataEncryptionKey(ctx, s)
+	if err != nil {
+		return fmt.Errorf("Failed to load data-encryption key: %w", err)
+	}
+
 	// Update storage backend to the database.
-	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
 		backend, err := database.GetStorageBackend(ctx, tx, name)
 		if err != nil {
 			return fmt.Errorf("Failed to retrieve storage backend details: %w", err)
@@ -105,9 +220,17 @@ func UpdateStorageBackend(ctx context.Context, s state.State, name string, backe
 		}
 		if config == "" {
 			config = backend.Config
+		} else {
+			config, err = encryptConfig(backendType, config, dek)
+			if err != nil {
+				return fmt.Errorf("Failed to encrypt storage backend config: %w", err)
+			}
 		}
 
-		err = database.UpdateStorageBackend(ctx, tx, name, database.StorageBackend{Name: name, Type: backendType, Principal: principal, ModelUUID: modelUUID, Config: config})
+		err = database.UpdateStorageBackendIfRevision(ctx, tx, name, expectedRevision, database.StorageBackend{Name: name, Type: backendType, Principal: principal, ModelUUID: modelUUID, Config: config})
+		if errors.Is(err, database.ErrRevisionMismatch) {
+			return api.StatusErrorf(http.StatusPreconditionFailed, "storage backend %q was concurrently modified", name)
+		}
 		if err != nil {
 			return fmt.Errorf("Failed to update record storage backend: %w", err)
 		}
@@ -118,10 +241,23 @@ func UpdateStorageBackend(ctx context.Context, s state.State, name string, backe
 	return err
 }
 
-// DeleteStorageBackend deletes a storage backend from database
-func DeleteStorageBackend(ctx context.Context, s state.State, name string) error {
+// DeleteStorageBackend deletes a storage backend from database.
+// expectedRevision must match the row's current revision; otherwise a 412
+// Precondition Failed api.StatusError is returned and nothing is deleted.
+// A nonexistent name yields a 404 api.StatusError rather than a 412, even
+// though the conditional delete below would also report no rows affected.
+func DeleteStorageBackend(ctx context.Context, s state.State, name string, expectedRevision int64) error {
 	return s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
-		return database.DeleteStorageBackend(ctx, tx, name)
-	})
+		_, err := database.GetStorageBackend(ctx, tx, name)
+		if err != nil {
+			return err
+		}
 
+		err = database.DeleteStorageBackendIfRevision(ctx, tx, name, expectedRevision)
+		if errors.Is(err, database.ErrRevisionMismatch) {
+			return api.StatusErrorf(http.StatusPreconditionFailed, "storage backend %q was concurrently modified", name)
+		}
+
+		return err
+	})
 }